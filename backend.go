@@ -0,0 +1,36 @@
+package pubsub
+
+// BackendMessage is a message as carried by a Backend. Hub decodes Payload
+// into M via its Codec before delivering it to local subscribers.
+type BackendMessage struct {
+	Topic   string
+	Payload []byte
+}
+
+// Backend lets a Hub route publish/subscribe traffic through an external
+// system (e.g. Redis) instead of keeping it entirely in-process. Hub calls
+// Subscribe/PSubscribe when the first local subscriber for a topic/pattern
+// appears and Unsubscribe/PUnsubscribe when the last one leaves.
+type Backend interface {
+	// Publish sends payload to topic.
+	Publish(topic string, payload []byte) error
+	// Subscribe starts receiving messages published to topics.
+	Subscribe(topics ...string) error
+	// PSubscribe starts receiving messages published to topics matching patterns.
+	PSubscribe(patterns ...string) error
+	// Unsubscribe stops receiving messages published to topics.
+	Unsubscribe(topics ...string) error
+	// PUnsubscribe stops receiving messages published to topics matching patterns.
+	PUnsubscribe(patterns ...string) error
+	// Messages returns the channel on which subscribed/psubscribed messages arrive.
+	Messages() <-chan BackendMessage
+	// Close releases the Backend's resources.
+	Close() error
+}
+
+// Codec encodes and decodes messages of type M to and from the bytes a
+// Backend carries.
+type Codec[M any] interface {
+	Encode(msg M) ([]byte, error)
+	Decode(data []byte) (M, error)
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/gobwas/glob"
+)
+
+// InProcessBackend is a Backend that routes messages entirely within the
+// process, matching the behaviour Hub had before Backend was introduced.
+type InProcessBackend struct {
+	mu       sync.RWMutex
+	topics   map[string]struct{}
+	patterns map[string]glob.Glob
+	messages chan BackendMessage
+}
+
+// NewInProcessBackend creates a new InProcessBackend.
+func NewInProcessBackend(queueSize int) *InProcessBackend {
+	return &InProcessBackend{
+		topics:   make(map[string]struct{}),
+		patterns: make(map[string]glob.Glob),
+		messages: make(chan BackendMessage, queueSize),
+	}
+}
+
+// Publish implements Backend.
+func (b *InProcessBackend) Publish(topic string, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, matched := b.topics[topic]
+	if !matched {
+		for _, g := range b.patterns {
+			if g.Match(topic) {
+				matched = true
+				break
+			}
+		}
+	}
+	if !matched {
+		return nil
+	}
+
+	b.messages <- BackendMessage{Topic: topic, Payload: payload}
+	return nil
+}
+
+// Subscribe implements Backend.
+func (b *InProcessBackend) Subscribe(topics ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		b.topics[topic] = struct{}{}
+	}
+	return nil
+}
+
+// PSubscribe implements Backend.
+func (b *InProcessBackend) PSubscribe(patterns ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pattern := range patterns {
+		b.patterns[pattern] = glob.MustCompile(pattern)
+	}
+	return nil
+}
+
+// Unsubscribe implements Backend.
+func (b *InProcessBackend) Unsubscribe(topics ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(b.topics, topic)
+	}
+	return nil
+}
+
+// PUnsubscribe implements Backend.
+func (b *InProcessBackend) PUnsubscribe(patterns ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pattern := range patterns {
+		delete(b.patterns, pattern)
+	}
+	return nil
+}
+
+// Messages implements Backend.
+func (b *InProcessBackend) Messages() <-chan BackendMessage {
+	return b.messages
+}
+
+// Close implements Backend.
+func (b *InProcessBackend) Close() error {
+	close(b.messages)
+	return nil
+}
@@ -0,0 +1,41 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// JSONCodec encodes messages as JSON, for use with a Backend such as Redis.
+type JSONCodec[M any] struct{}
+
+// Encode implements Codec.
+func (JSONCodec[M]) Encode(msg M) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// Decode implements Codec.
+func (JSONCodec[M]) Decode(data []byte) (M, error) {
+	var msg M
+	err := json.Unmarshal(data, &msg)
+	return msg, err
+}
+
+// GobCodec encodes messages using encoding/gob, for use with a Backend such as Redis.
+type GobCodec[M any] struct{}
+
+// Encode implements Codec.
+func (GobCodec[M]) Encode(msg M) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (GobCodec[M]) Decode(data []byte) (M, error) {
+	var msg M
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&msg)
+	return msg, err
+}
@@ -0,0 +1,37 @@
+package pubsub
+
+import "testing"
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	c := JSONCodec[string]{}
+
+	payload, err := c.Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got, err := c.Decode(payload)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != "hello" {
+		t.Fatalf("got %q, want %q", got, "hello")
+	}
+}
+
+func TestGobCodecRoundTrip(t *testing.T) {
+	c := GobCodec[string]{}
+
+	payload, err := c.Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	got, err := c.Decode(payload)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got != "hello" {
+		t.Fatalf("got %q, want %q", got, "hello")
+	}
+}
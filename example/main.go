@@ -9,29 +9,29 @@ import (
 
 func main() {
 
-	h := pubsub.NewHub(32)
+	h := pubsub.NewHub[string](32)
 
-	subChan11 := h.Sub("/some/topic/1")
+	subChan11, unsub11, _ := h.Sub("/some/topic/1")
 	go func() {
 		for msg := range subChan11 {
 			fmt.Printf("subChan11, TOPIC: /some/topic/1, MSG: %s\n", msg)
 		}
 	}()
-	subChan12 := h.Sub("/some/topic/1")
+	subChan12, unsub12, _ := h.Sub("/some/topic/1")
 	go func() {
 		for msg := range subChan12 {
 			fmt.Printf("subChan12, TOPIC: /some/topic/1, MSG: %s\n", msg)
 		}
 	}()
 
-	subChan2 := h.Sub("/some/topic/2")
+	subChan2, unsub2, _ := h.Sub("/some/topic/2")
 	go func() {
 		for msg := range subChan2 {
 			fmt.Printf("subChan2,  TOPIC: /some/topic/2, MSG: %s\n", msg)
 		}
 	}()
 
-	subChan3 := h.PSub("/some/topic/*")
+	subChan3, unsub3, _ := h.PSub("/some/topic/*")
 	go func() {
 		for msg := range subChan3 {
 			fmt.Printf("subChan3,  TOPIC: /some/topic/*, MSG: %s\n", msg)
@@ -46,8 +46,8 @@ func main() {
 	h.Pub("/some/topic/3", "message 6 !")
 
 	<-time.After(time.Second)
-	h.Unsub("/some_topic/1", subChan11)
-	h.Unsub("/some_topic/1", subChan12)
-	h.Unsub("/some_topic/2", subChan2)
-	h.PUnsub("/some_topic/*", subChan3)
+	unsub11()
+	unsub12()
+	unsub2()
+	unsub3()
 }
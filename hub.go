@@ -2,62 +2,260 @@ package pubsub
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/gobwas/glob"
 )
 
-// Hub represents PubSubHub.
-type Hub interface {
+// ErrTopicAlreadyBridged returns when AddTopic is called twice for the same name.
+var ErrTopicAlreadyBridged = errors.New("topic is already bridged")
+
+// bridge tracks one AddTopic goroutine. RemoveTopic only cancels it; the
+// goroutine deletes its own entry from hub.bridges when it exits, so AddTopic
+// for the same name returns ErrTopicAlreadyBridged until then.
+type bridge struct {
+	cancel context.CancelFunc
+}
+
+// Hub represents PubSubHub. M is the type of message carried by its topics.
+type Hub[M any] interface {
 	// Pub publishes message.
-	Pub(topic string, msg interface{})
-	// Sub adds subscription and returns channel to subscribe.
-	Sub(topic string) <-chan interface{}
-	// Unsub removes subscription corresponding to the submitted channel.
-	Unsub(topic string, ch <-chan interface{})
-	// PSub adds pattern subscriptions and returns channel to subscribe.
-	PSub(pattern string) <-chan interface{}
+	Pub(topic string, msg M)
+	// Sub adds subscription and returns a channel to subscribe along with a
+	// function that removes exactly this subscription.
+	Sub(topic string) (<-chan M, UnsubscribeFunc, error)
+	// SubWithOptions adds subscription with delivery options and returns a
+	// channel to subscribe along with a function that removes exactly this
+	// subscription.
+	SubWithOptions(topic string, opts SubOptions) (<-chan M, UnsubscribeFunc, error)
+	// Unsub removes subscription corresponding to the submitted channel. It
+	// is kept for callers that predate UnsubscribeFunc; prefer the func
+	// returned from Sub/SubWithOptions.
+	Unsub(topic string, ch <-chan M)
+	// PSub adds pattern subscriptions and returns a channel to subscribe
+	// along with a function that removes exactly this subscription.
+	PSub(pattern string) (<-chan M, UnsubscribeFunc, error)
+	// PSubWithOptions adds pattern subscription with delivery options and
+	// returns a channel to subscribe along with a function that removes
+	// exactly this subscription.
+	PSubWithOptions(pattern string, opts SubOptions) (<-chan M, UnsubscribeFunc, error)
+	// SubWithReplay adds a subscription that first receives the topic's
+	// retained backlog before live traffic, then behaves like Sub.
+	SubWithReplay(topic string, n int) (<-chan M, UnsubscribeFunc, error)
 	// PUnsub removes pattern subscription corresponding to the submitted channel.
-	PUnsub(pattern string, ch <-chan interface{})
+	PUnsub(pattern string, ch <-chan M)
+	// EnsureTopic creates the named topic with the given options if it does
+	// not already exist. Leaving opts.Retain at zero falls back to the Hub's
+	// DefaultRetain/DefaultRetainTTL; set it to a negative value to opt this
+	// topic out of that default, or to a positive value to override it. This is the only way to make a topic
+	// retain, since topics created on demand by Sub/PSub never do.
+	EnsureTopic(topic string, opts TopicOptions)
+	// AddTopic bridges an external channel into the Hub as a topic: every
+	// value read from src is published under name, so both direct and
+	// pattern subscribers of name see it. The bridging goroutine exits when
+	// src is closed, RemoveTopic(name) is called, or the Hub is closed.
+	AddTopic(name string, src <-chan M) error
+	// RemoveTopic stops bridging the channel registered for name via AddTopic.
+	RemoveTopic(name string)
+	// Topics returns the names of topics currently registered on the Hub:
+	// those with a local subscriber, those created via EnsureTopic (a
+	// retaining topic stays registered after its last unsubscribe), and
+	// names bridged via AddTopic.
+	Topics() []string
+	// Patterns returns the patterns with at least one local subscriber.
+	Patterns() []string
+	// Stats returns per-subscriber delivery stats for a topic, nil if the topic does not exist.
+	Stats(topic string) map[<-chan M]SubStats
 	// Close closes Hub.
 	Close()
 	// Context returns context.
 	Context() context.Context
 }
 
+// HubAny is a Hub carrying untyped messages, kept for callers that predate
+// generics and cannot name a concrete message type.
+type HubAny = Hub[any]
+
+// HubOptions configures a Hub created via NewHubWithOptions.
+type HubOptions struct {
+	// QueueSize is the buffer size used for each topic's internal publish queue.
+	QueueSize int
+	// DefaultRetain is the retention applied to topics pre-created via
+	// EnsureTopic when the call leaves TopicOptions.Retain at zero; it has no
+	// effect on topics created lazily by Sub/PSub. A retaining topic survives
+	// its last unsubscribe instead of being torn down, so a lazily-created
+	// topic that auto-retained would never be collected once the keyspace
+	// churns; call EnsureTopic up front for any topic that needs a backlog.
+	DefaultRetain int
+	// DefaultRetainTTL is the retention TTL applied alongside DefaultRetain.
+	DefaultRetainTTL time.Duration
+}
+
 // NewHub creates a new Hub.
-func NewHub(queueSize int) Hub {
+func NewHub[M any](queueSize int) Hub[M] {
+	return NewHubWithOptions[M](HubOptions{QueueSize: queueSize})
+}
+
+// NewHubAny creates a new HubAny.
+func NewHubAny(queueSize int) HubAny {
+	return NewHub[any](queueSize)
+}
+
+// NewHubWithOptions creates a new Hub whose default retention options apply
+// to topics pre-created via EnsureTopic; topics created on demand by Sub/PSub
+// are never auto-retained, so an unbounded keyspace of them can't pin topics
+// (and their delivery goroutines) in memory forever.
+func NewHubWithOptions[M any](opts HubOptions) Hub[M] {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &hub{
+	return &hub[M]{
+		queueSize: opts.QueueSize,
+		retain:    opts.DefaultRetain,
+		retainTTL: opts.DefaultRetainTTL,
+		topics:    make(map[string]Topic[M]),
+		ptopics:   make(map[string]Topic[M]),
+		regexps:   make(map[string]glob.Glob),
+		bridges:   make(map[string]*bridge),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// NewHubWithBackend creates a new Hub whose publish/subscribe traffic is
+// routed through backend (e.g. Redis) instead of staying entirely
+// in-process, using JSONCodec to convert messages to and from the bytes
+// backend carries. The Hub API surface is unchanged; only the transport is.
+func NewHubWithBackend[M any](backend Backend, queueSize int) Hub[M] {
+	return NewHubWithBackendAndCodec[M](backend, JSONCodec[M]{}, queueSize)
+}
+
+// NewHubWithBackendAndCodec is NewHubWithBackend with the message codec
+// chosen explicitly (e.g. GobCodec) instead of defaulting to JSONCodec.
+func NewHubWithBackendAndCodec[M any](backend Backend, codec Codec[M], queueSize int) Hub[M] {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &hub[M]{
 		queueSize: queueSize,
-		topics:    make(map[string]Topic),
-		ptopics:   make(map[string]Topic),
+		topics:    make(map[string]Topic[M]),
+		ptopics:   make(map[string]Topic[M]),
 		regexps:   make(map[string]glob.Glob),
+		bridges:   make(map[string]*bridge),
+		backend:   backend,
+		codec:     codec,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
+
+	go h.runBackend()
+	return h
 }
 
-type hub struct {
+type hub[M any] struct {
 	queueSize int
-	topics    map[string]Topic
-	ptopics   map[string]Topic
+	retain    int
+	retainTTL time.Duration
+	topics    map[string]Topic[M]
+	ptopics   map[string]Topic[M]
 	regexps   map[string]glob.Glob
+	bridges   map[string]*bridge
+	backend   Backend
+	codec     Codec[M]
 	mu        sync.RWMutex
 	ctx       context.Context
 	cancel    context.CancelFunc
 }
 
-func (h *hub) Pub(topic string, msg interface{}) {
+// runBackend fans incoming BackendMessages out to local subscribers and
+// closes backend once the Hub is closed.
+func (h *hub[M]) runBackend() {
+	go func() {
+		<-h.ctx.Done()
+		_ = h.backend.Close()
+	}()
+
+	for bm := range h.backend.Messages() {
+		msg, err := h.codec.Decode(bm.Payload)
+		if err != nil {
+			continue
+		}
+		h.dispatch(bm.Topic, msg)
+	}
+}
+
+// dispatch delivers msg, published under topic, to every matching local
+// subscriber. With a Backend, this is the only path messages reach local
+// subscribers through, including messages this Hub itself published.
+func (h *hub[M]) dispatch(topic string, msg M) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	// sub
-	t, ok := h.topics[topic]
-	if !ok {
+	if t, ok := h.topics[topic]; ok {
+		_ = t.Pub(msg)
+	}
+	for pattern, t := range h.ptopics {
+		if h.regexps[pattern].Match(topic) {
+			_ = t.Pub(msg)
+		}
+	}
+}
+
+// newTopic creates a topic for a name or pattern seen for the first time via
+// Sub/PSub. It never retains: a lazily-created topic name is, by definition,
+// caller-chosen and unbounded, so auto-retaining it would pin an ever-growing
+// set of topics (and their delivery goroutines) in memory past their last
+// subscriber. Callers that need retention on such a topic must pre-create it
+// with EnsureTopic.
+func (h *hub[M]) newTopic() Topic[M] {
+	return NewTopicWithOptions[M](h.ctx, TopicOptions{
+		QueueSize: h.queueSize,
+	})
+}
+
+func (h *hub[M]) subscribeBackend(topic string) {
+	if h.backend == nil {
+		return
+	}
+	_ = h.backend.Subscribe(topic)
+}
+
+func (h *hub[M]) unsubscribeBackend(topic string) {
+	if h.backend == nil {
+		return
+	}
+	_ = h.backend.Unsubscribe(topic)
+}
+
+func (h *hub[M]) psubscribeBackend(pattern string) {
+	if h.backend == nil {
 		return
 	}
-	_ = t.Pub(msg)
+	_ = h.backend.PSubscribe(pattern)
+}
+
+func (h *hub[M]) punsubscribeBackend(pattern string) {
+	if h.backend == nil {
+		return
+	}
+	_ = h.backend.PUnsubscribe(pattern)
+}
+
+func (h *hub[M]) Pub(topic string, msg M) {
+	if h.backend != nil {
+		payload, err := h.codec.Encode(msg)
+		if err != nil {
+			return
+		}
+		_ = h.backend.Publish(topic, payload)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// sub
+	if t, ok := h.topics[topic]; ok {
+		_ = t.Pub(msg)
+	}
 
 	// psub
 	for pattern, t := range h.ptopics {
@@ -67,21 +265,71 @@ func (h *hub) Pub(topic string, msg interface{}) {
 	}
 }
 
-func (h *hub) Sub(topic string) <-chan interface{} {
+func (h *hub[M]) Sub(topic string) (<-chan M, UnsubscribeFunc, error) {
+	return h.SubWithOptions(topic, SubOptions{Policy: Block})
+}
+
+func (h *hub[M]) SubWithOptions(topic string, opts SubOptions) (<-chan M, UnsubscribeFunc, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	t, ok := h.topics[topic]
 	if !ok {
-		t = NewTopic(h.ctx, h.queueSize)
+		t = h.newTopic()
 		h.topics[topic] = t
+		h.subscribeBackend(topic)
+	}
+
+	ch, unsub, err := t.SubWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	ch, _ := t.Sub()
-	return ch
+	return ch, func() {
+		unsub()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if cur, ok := h.topics[topic]; ok && cur == t && t.SubLen() == 0 && !t.Retains() {
+			t.Close()
+			delete(h.topics, topic)
+			h.unsubscribeBackend(topic)
+		}
+	}, nil
 }
 
-func (h *hub) Unsub(topic string, ch <-chan interface{}) {
+func (h *hub[M]) SubWithReplay(topic string, n int) (<-chan M, UnsubscribeFunc, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[topic]
+	if !ok {
+		t = h.newTopic()
+		h.topics[topic] = t
+		h.subscribeBackend(topic)
+	}
+
+	ch, unsub, err := t.SubWithReplay(n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ch, func() {
+		unsub()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if cur, ok := h.topics[topic]; ok && cur == t && t.SubLen() == 0 && !t.Retains() {
+			t.Close()
+			delete(h.topics, topic)
+			h.unsubscribeBackend(topic)
+		}
+	}, nil
+}
+
+func (h *hub[M]) Unsub(topic string, ch <-chan M) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -91,28 +339,49 @@ func (h *hub) Unsub(topic string, ch <-chan interface{}) {
 	}
 	_ = t.Unsub(ch)
 
-	if t.SubLen() == 0 {
+	if t.SubLen() == 0 && !t.Retains() {
 		t.Close()
 		delete(h.topics, topic)
+		h.unsubscribeBackend(topic)
 	}
 }
 
-func (h *hub) PSub(pattern string) <-chan interface{} {
+func (h *hub[M]) PSub(pattern string) (<-chan M, UnsubscribeFunc, error) {
+	return h.PSubWithOptions(pattern, SubOptions{Policy: Block})
+}
+
+func (h *hub[M]) PSubWithOptions(pattern string, opts SubOptions) (<-chan M, UnsubscribeFunc, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	t, ok := h.ptopics[pattern]
 	if !ok {
-		t = NewTopic(h.ctx, h.queueSize)
+		t = h.newTopic()
 		h.ptopics[pattern] = t
 		h.regexps[pattern] = glob.MustCompile(pattern)
+		h.psubscribeBackend(pattern)
+	}
+
+	ch, unsub, err := t.SubWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	ch, _ := t.Sub()
-	return ch
+	return ch, func() {
+		unsub()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if cur, ok := h.ptopics[pattern]; ok && cur == t && t.SubLen() == 0 && !t.Retains() {
+			t.Close()
+			delete(h.ptopics, pattern)
+			h.punsubscribeBackend(pattern)
+		}
+	}, nil
 }
 
-func (h *hub) PUnsub(pattern string, ch <-chan interface{}) {
+func (h *hub[M]) PUnsub(pattern string, ch <-chan M) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -122,16 +391,124 @@ func (h *hub) PUnsub(pattern string, ch <-chan interface{}) {
 	}
 	_ = t.Unsub(ch)
 
-	if t.SubLen() == 0 {
+	if t.SubLen() == 0 && !t.Retains() {
 		t.Close()
 		delete(h.ptopics, pattern)
+		h.punsubscribeBackend(pattern)
+	}
+}
+
+func (h *hub[M]) EnsureTopic(topic string, opts TopicOptions) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.topics[topic]; ok {
+		return
+	}
+
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = h.queueSize
+	}
+	if opts.Retain == 0 {
+		opts.Retain = h.retain
+		opts.RetainTTL = h.retainTTL
+	}
+	h.topics[topic] = NewTopicWithOptions[M](h.ctx, opts)
+	h.subscribeBackend(topic)
+}
+
+func (h *hub[M]) AddTopic(name string, src <-chan M) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.bridges[name]; ok {
+		return ErrTopicAlreadyBridged
+	}
+
+	ctx, cancel := context.WithCancel(h.ctx)
+	b := &bridge{cancel: cancel}
+	h.bridges[name] = b
+
+	go func() {
+		defer func() {
+			h.mu.Lock()
+			if h.bridges[name] == b {
+				delete(h.bridges, name)
+			}
+			h.mu.Unlock()
+		}()
+
+		for {
+			select {
+			case msg, ok := <-src:
+				if !ok {
+					return
+				}
+				h.Pub(name, msg)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (h *hub[M]) RemoveTopic(name string) {
+	h.mu.Lock()
+	b, ok := h.bridges[name]
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	b.cancel()
+}
+
+func (h *hub[M]) Topics() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(h.topics)+len(h.bridges))
+	names := make([]string, 0, len(h.topics)+len(h.bridges))
+	for name := range h.topics {
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	for name := range h.bridges {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func (h *hub[M]) Patterns() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	patterns := make([]string, 0, len(h.ptopics))
+	for pattern := range h.ptopics {
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+func (h *hub[M]) Stats(topic string) map[<-chan M]SubStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	t, ok := h.topics[topic]
+	if !ok {
+		return nil
 	}
+	return t.Stats()
 }
 
-func (h *hub) Close() {
+func (h *hub[M]) Close() {
 	h.cancel()
 }
 
-func (h *hub) Context() context.Context {
+func (h *hub[M]) Context() context.Context {
 	return h.ctx
 }
@@ -0,0 +1,153 @@
+package pubsub
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHubStaleUnsubscribeDoesNotEvictNewTopic guards against a stale
+// UnsubscribeFunc tearing down whatever topic currently lives under the same
+// name after it was recreated.
+func TestHubStaleUnsubscribeDoesNotEvictNewTopic(t *testing.T) {
+	h := NewHub[string](1)
+	defer h.Close()
+
+	_, unsubA1, err := h.Sub("a")
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	unsubA1()
+
+	chB, _, err := h.Sub("a")
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+
+	// Re-invoking the first, already-torn-down subscription's UnsubscribeFunc
+	// must not evict the second, live topic registered under the same name.
+	unsubA1()
+
+	h.Pub("a", "hello")
+
+	select {
+	case msg := <-chB:
+		if msg != "hello" {
+			t.Fatalf("got %q, want %q", msg, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("live subscriber did not receive the message; stale unsubscribe evicted its topic")
+	}
+
+	if topics := h.Topics(); len(topics) != 1 || topics[0] != "a" {
+		t.Fatalf("Topics() = %v, want [a]", topics)
+	}
+}
+
+// TestHubDefaultRetainDoesNotPinLazyTopics guards against DefaultRetain
+// turning every lazily Sub/PSub-created topic into one that never gets torn
+// down.
+func TestHubDefaultRetainDoesNotPinLazyTopics(t *testing.T) {
+	h := NewHubWithOptions[string](HubOptions{QueueSize: 1, DefaultRetain: 5})
+	defer h.Close()
+
+	_, unsub, err := h.Sub("ephemeral")
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	unsub()
+
+	if topics := h.Topics(); len(topics) != 0 {
+		t.Fatalf("Topics() = %v, want [] once the only subscriber of a lazily-created topic leaves", topics)
+	}
+}
+
+// TestHubEnsureTopicRetentionSurvivesLastUnsubscribe checks that a topic
+// explicitly pre-created with retention keeps its backlog available to a
+// late SubWithReplay subscriber even after every earlier subscriber left.
+func TestHubEnsureTopicRetentionSurvivesLastUnsubscribe(t *testing.T) {
+	h := NewHubWithOptions[string](HubOptions{QueueSize: 1})
+	defer h.Close()
+
+	h.EnsureTopic("sticky", TopicOptions{QueueSize: 1, Retain: 5})
+
+	ch, unsub, err := h.Sub("sticky")
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	h.Pub("sticky", "keep-me")
+	<-ch
+	unsub()
+
+	if topics := h.Topics(); len(topics) != 1 || topics[0] != "sticky" {
+		t.Fatalf("Topics() = %v, want [sticky] (a retaining topic must survive its last unsubscribe)", topics)
+	}
+
+	replay, unsubReplay, err := h.SubWithReplay("sticky", -1)
+	if err != nil {
+		t.Fatalf("SubWithReplay: %v", err)
+	}
+	defer unsubReplay()
+
+	select {
+	case msg := <-replay:
+		if msg != "keep-me" {
+			t.Fatalf("replay got %q, want %q", msg, "keep-me")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("retained message was not replayed to the late subscriber")
+	}
+}
+
+// TestHubAddTopicBridgesAndRemoveTopicStops checks that a bridged channel's
+// values reach both direct and pattern subscribers of the bridged name, and
+// that RemoveTopic stops the bridging goroutine.
+func TestHubAddTopicBridgesAndRemoveTopicStops(t *testing.T) {
+	h := NewHub[string](1)
+	defer h.Close()
+
+	direct, unsubDirect, err := h.Sub("bridged")
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	defer unsubDirect()
+
+	pat, unsubPat, err := h.PSub("bridged*")
+	if err != nil {
+		t.Fatalf("PSub: %v", err)
+	}
+	defer unsubPat()
+
+	src := make(chan string, 1)
+	if err := h.AddTopic("bridged", src); err != nil {
+		t.Fatalf("AddTopic: %v", err)
+	}
+
+	src <- "event"
+
+	for _, ch := range []<-chan string{direct, pat} {
+		select {
+		case msg := <-ch:
+			if msg != "event" {
+				t.Fatalf("got %q, want %q", msg, "event")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscriber did not see the bridged event")
+		}
+	}
+
+	if topics := h.Topics(); len(topics) != 1 || topics[0] != "bridged" {
+		t.Fatalf("Topics() = %v, want [bridged]", topics)
+	}
+
+	h.RemoveTopic("bridged")
+
+	hh := h.(*hub[string])
+	waitFor(t, time.Second, func() bool {
+		hh.mu.RLock()
+		defer hh.mu.RUnlock()
+		_, stillBridged := hh.bridges["bridged"]
+		return !stillBridged
+	})
+
+	close(src)
+}
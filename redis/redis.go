@@ -0,0 +1,181 @@
+// Package redis provides a pubsub.Backend backed by Redis Pub/Sub, so a Hub
+// can fan out across a fleet instead of staying in one process.
+package redis
+
+import (
+	"context"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	pubsub "github.com/ryskiwt/pubsub-go"
+)
+
+// pubsubConn is the subset of *redis.PubSub that Backend drives. It exists so
+// tests can swap in a fake connection to exercise reconnect/resubscribe
+// without a live Redis server.
+type pubsubConn interface {
+	Subscribe(ctx context.Context, channels ...string) error
+	PSubscribe(ctx context.Context, patterns ...string) error
+	Unsubscribe(ctx context.Context, channels ...string) error
+	PUnsubscribe(ctx context.Context, patterns ...string) error
+	ReceiveMessage(ctx context.Context) (*goredis.Message, error)
+	Close() error
+}
+
+// Backend is a pubsub.Backend backed by Redis SUBSCRIBE/PSUBSCRIBE. On
+// connection loss it re-issues every tracked subscription against the new
+// connection before resuming delivery, so subscribers don't silently stop
+// receiving messages.
+type Backend struct {
+	client *goredis.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	newConn func(context.Context) pubsubConn
+
+	mu       sync.Mutex
+	topics   map[string]struct{}
+	patterns map[string]struct{}
+	conn     pubsubConn
+
+	messages chan pubsub.BackendMessage
+}
+
+// NewBackend creates a Backend that publishes and subscribes through client.
+func NewBackend(client *goredis.Client) *Backend {
+	ctx, cancel := context.WithCancel(context.Background())
+	newConn := func(ctx context.Context) pubsubConn { return client.Subscribe(ctx) }
+	b := &Backend{
+		client:   client,
+		ctx:      ctx,
+		cancel:   cancel,
+		newConn:  newConn,
+		topics:   make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+		conn:     newConn(ctx),
+		messages: make(chan pubsub.BackendMessage),
+	}
+
+	go b.run()
+	return b
+}
+
+func (b *Backend) run() {
+	for {
+		b.mu.Lock()
+		conn := b.conn
+		b.mu.Unlock()
+
+		msg, err := conn.ReceiveMessage(b.ctx)
+		if err != nil {
+			select {
+			case <-b.ctx.Done():
+				close(b.messages)
+				return
+			default:
+			}
+
+			b.reconnect()
+			continue
+		}
+
+		select {
+		case b.messages <- pubsub.BackendMessage{Topic: msg.Channel, Payload: []byte(msg.Payload)}:
+		case <-b.ctx.Done():
+			close(b.messages)
+			return
+		}
+	}
+}
+
+// reconnect replaces the underlying *redis.PubSub and re-issues every
+// currently tracked subscription and pattern subscription, matching the
+// resubscribe pattern used by mature Redis clients.
+func (b *Backend) reconnect() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_ = b.conn.Close()
+	b.conn = b.newConn(b.ctx)
+
+	if len(b.topics) > 0 {
+		topics := make([]string, 0, len(b.topics))
+		for topic := range b.topics {
+			topics = append(topics, topic)
+		}
+		_ = b.conn.Subscribe(b.ctx, topics...)
+	}
+
+	if len(b.patterns) > 0 {
+		patterns := make([]string, 0, len(b.patterns))
+		for pattern := range b.patterns {
+			patterns = append(patterns, pattern)
+		}
+		_ = b.conn.PSubscribe(b.ctx, patterns...)
+	}
+}
+
+// Publish implements pubsub.Backend.
+func (b *Backend) Publish(topic string, payload []byte) error {
+	return b.client.Publish(b.ctx, topic, payload).Err()
+}
+
+// Subscribe implements pubsub.Backend.
+func (b *Backend) Subscribe(topics ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		b.topics[topic] = struct{}{}
+	}
+	return b.conn.Subscribe(b.ctx, topics...)
+}
+
+// PSubscribe implements pubsub.Backend.
+func (b *Backend) PSubscribe(patterns ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pattern := range patterns {
+		b.patterns[pattern] = struct{}{}
+	}
+	return b.conn.PSubscribe(b.ctx, patterns...)
+}
+
+// Unsubscribe implements pubsub.Backend.
+func (b *Backend) Unsubscribe(topics ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(b.topics, topic)
+	}
+	return b.conn.Unsubscribe(b.ctx, topics...)
+}
+
+// PUnsubscribe implements pubsub.Backend.
+func (b *Backend) PUnsubscribe(patterns ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pattern := range patterns {
+		delete(b.patterns, pattern)
+	}
+	return b.conn.PUnsubscribe(b.ctx, patterns...)
+}
+
+// Messages implements pubsub.Backend.
+func (b *Backend) Messages() <-chan pubsub.BackendMessage {
+	return b.messages
+}
+
+// Close implements pubsub.Backend.
+func (b *Backend) Close() error {
+	b.cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.conn.Close()
+}
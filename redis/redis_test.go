@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	pubsub "github.com/ryskiwt/pubsub-go"
+)
+
+// fakeConn is a pubsubConn whose ReceiveMessage fails exactly once, to drive
+// Backend.reconnect, and which records every Subscribe/PSubscribe call so a
+// test can assert the resubscribe replay against it.
+type fakeConn struct {
+	mu          sync.Mutex
+	failOnce    bool
+	failed      bool
+	subscribed  []string
+	psubscribed []string
+	messages    chan *goredis.Message
+	closed      chan struct{}
+}
+
+func newFakeConn(failOnce bool) *fakeConn {
+	return &fakeConn{
+		failOnce: failOnce,
+		messages: make(chan *goredis.Message, 1),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (c *fakeConn) Subscribe(ctx context.Context, channels ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribed = append(c.subscribed, channels...)
+	return nil
+}
+
+func (c *fakeConn) PSubscribe(ctx context.Context, patterns ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.psubscribed = append(c.psubscribed, patterns...)
+	return nil
+}
+
+func (c *fakeConn) Unsubscribe(ctx context.Context, channels ...string) error  { return nil }
+func (c *fakeConn) PUnsubscribe(ctx context.Context, patterns ...string) error { return nil }
+
+func (c *fakeConn) ReceiveMessage(ctx context.Context) (*goredis.Message, error) {
+	c.mu.Lock()
+	if c.failOnce && !c.failed {
+		c.failed = true
+		c.mu.Unlock()
+		return nil, errors.New("connection reset")
+	}
+	c.mu.Unlock()
+
+	select {
+	case msg := <-c.messages:
+		return msg, nil
+	case <-c.closed:
+		return nil, errors.New("closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *fakeConn) Close() error {
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	return nil
+}
+
+func (c *fakeConn) subscribedTo() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.subscribed...)
+}
+
+func (c *fakeConn) psubscribedTo() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.psubscribed...)
+}
+
+// TestBackendReconnectResubscribes checks that after a ReceiveMessage error,
+// Backend opens a new connection and replays every tracked Subscribe and
+// PSubscribe call onto it before resuming delivery.
+func TestBackendReconnectResubscribes(t *testing.T) {
+	first := newFakeConn(true)
+	second := newFakeConn(false)
+	conns := []*fakeConn{second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := &Backend{
+		ctx:      ctx,
+		cancel:   cancel,
+		topics:   make(map[string]struct{}),
+		patterns: make(map[string]struct{}),
+		conn:     first,
+		messages: make(chan pubsub.BackendMessage),
+	}
+	b.newConn = func(context.Context) pubsubConn {
+		conn := conns[0]
+		conns = conns[1:]
+		return conn
+	}
+
+	if err := b.Subscribe("room"); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := b.PSubscribe("room.*"); err != nil {
+		t.Fatalf("PSubscribe: %v", err)
+	}
+
+	go b.run()
+	defer b.Close()
+
+	second.messages <- &goredis.Message{Channel: "room", Payload: "hello"}
+
+	select {
+	case got := <-b.Messages():
+		if got.Topic != "room" || string(got.Payload) != "hello" {
+			t.Fatalf("got %+v, want Topic=room Payload=hello", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for post-reconnect message")
+	}
+
+	if got := second.subscribedTo(); len(got) != 1 || got[0] != "room" {
+		t.Fatalf("second connection Subscribe calls = %v, want [room]", got)
+	}
+	if got := second.psubscribedTo(); len(got) != 1 || got[0] != "room.*" {
+		t.Fatalf("second connection PSubscribe calls = %v, want [room.*]", got)
+	}
+}
@@ -4,51 +4,191 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ErrTopicAlreadyClosed returns when the topic is already closed.
 var ErrTopicAlreadyClosed = errors.New("topic is already closed")
 
+// DeliveryPolicy controls what a Topic does for a subscriber whose buffer is
+// full at publish time.
+type DeliveryPolicy int
+
+const (
+	// Block waits until the subscriber has room, as a plain Sub does. Other
+	// subscribers are always delivered to first, so a stalled Block
+	// subscriber can't stop them from getting a message; it does still tie
+	// up the topic's publish goroutine (and therefore Sub/Unsub/Stats, which
+	// share its lock) until room frees up or the consumer goes away.
+	Block DeliveryPolicy = iota
+	// DropNewest discards the message currently being published.
+	DropNewest
+	// DropOldest discards the oldest buffered message to make room for the new one.
+	DropOldest
+	// Disconnect unsubscribes the subscriber, closes its channel, and invokes
+	// its SubOptions.OnSubscriberDropped callback if set.
+	Disconnect
+)
+
+// SubOptions configures a subscription created via SubWithOptions.
+type SubOptions struct {
+	// Policy selects what happens when the subscriber's buffer is full.
+	// The zero value is Block.
+	Policy DeliveryPolicy
+	// BufferSize overrides the channel buffer size for this subscriber.
+	// Zero uses the topic's queue size.
+	BufferSize int
+	// OnSubscriberDropped, if set, is called once when the Disconnect policy
+	// closes this subscriber's channel because its buffer was full. It runs
+	// on the topic's publish goroutine, so it must not block or call back
+	// into the topic.
+	OnSubscriberDropped func()
+}
+
+// SubStats reports delivery counters for one subscriber.
+type SubStats struct {
+	// Delivered is the number of messages handed to the subscriber's channel.
+	Delivered int64
+	// Dropped is the number of messages discarded because of its DeliveryPolicy.
+	Dropped int64
+	// LastDropAt is the time of the most recent drop, zero if none occurred.
+	LastDropAt time.Time
+}
+
+// UnsubscribeFunc removes exactly the subscription it was returned from,
+// regardless of what happens to the topic name or pattern afterwards.
+type UnsubscribeFunc func()
+
+// TopicOptions configures a Topic created via NewTopicWithOptions.
+type TopicOptions struct {
+	// QueueSize is the buffer size of the topic's internal publish queue.
+	QueueSize int
+	// Retain is the number of most recently published messages to keep for
+	// subscribers that join via SubWithReplay. Zero disables retention.
+	Retain int
+	// RetainTTL discards retained messages older than this duration.
+	// Zero means retained messages never expire on their own.
+	RetainTTL time.Duration
+}
+
 // Topic represents message publish/subscribe interface for one topic.
-type Topic interface {
+// M is the type of message carried by the topic.
+type Topic[M any] interface {
 	// Pub publishes message.
-	Pub(msg interface{}) error
-	// Sub adds subscription and returns channel to subscribe.
-	Sub() (<-chan interface{}, error)
-	// Unsub removes subscription corresponding to the submitted channel.
-	Unsub(ch <-chan interface{}) error
+	Pub(msg M) error
+	// Sub adds subscription and returns a channel to subscribe along with a
+	// function that removes exactly this subscription.
+	Sub() (<-chan M, UnsubscribeFunc, error)
+	// SubWithOptions adds subscription with delivery options and returns a
+	// channel to subscribe along with a function that removes exactly this
+	// subscription.
+	SubWithOptions(opts SubOptions) (<-chan M, UnsubscribeFunc, error)
+	// SubWithReplay adds a subscription that first receives up to n retained
+	// messages before live traffic, then behaves like Sub. n < 0 replays the
+	// full retained backlog.
+	SubWithReplay(n int) (<-chan M, UnsubscribeFunc, error)
+	// Unsub removes subscription corresponding to the submitted channel. It
+	// is kept for callers that predate UnsubscribeFunc; prefer the func
+	// returned from Sub/SubWithOptions.
+	Unsub(ch <-chan M) error
 	// SubLen returns length of subscribers.
 	SubLen() int
+	// Retains reports whether the topic keeps a retention ring for
+	// SubWithReplay, i.e. whether it was created with Retain > 0.
+	Retains() bool
+	// Stats returns per-subscriber delivery stats keyed by subscription channel.
+	Stats() map[<-chan M]SubStats
 	// Close closes Topic.
 	Close()
 	// Context returns context.
 	Context() context.Context
 }
 
+// TopicAny is a Topic carrying untyped messages, kept for callers that
+// predate generics and cannot name a concrete message type.
+type TopicAny = Topic[any]
+
 // NewTopic creates a new Topic instance.
-func NewTopic(ctx context.Context, queueSize int) Topic {
+func NewTopic[M any](ctx context.Context, queueSize int) Topic[M] {
+	return NewTopicWithOptions[M](ctx, TopicOptions{QueueSize: queueSize})
+}
+
+// NewTopicWithOptions creates a new Topic instance with retention options.
+func NewTopicWithOptions[M any](ctx context.Context, opts TopicOptions) Topic[M] {
 	ctx, cancel := context.WithCancel(ctx)
-	t := topic{
-		pubChan:  make(chan interface{}, queueSize),
-		subChans: make(map[<-chan interface{}]chan interface{}),
-		ctx:      ctx,
-		cancel:   cancel,
+	t := topic[M]{
+		pubChan:   make(chan M, opts.QueueSize),
+		subs:      make(map[uint64]*subscriber[M]),
+		chanIDs:   make(map[<-chan M]uint64),
+		retainCap: opts.Retain,
+		retainTTL: opts.RetainTTL,
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 
 	go t.run()
 	return &t
 }
 
-type topic struct {
-	count    int64
-	pubChan  chan interface{}
-	subChans map[<-chan interface{}]chan interface{}
-	mu       sync.RWMutex
-	ctx      context.Context
-	cancel   context.CancelFunc
+// NewTopicAny creates a new TopicAny instance.
+func NewTopicAny(ctx context.Context, queueSize int) TopicAny {
+	return NewTopic[any](ctx, queueSize)
+}
+
+type subscriber[M any] struct {
+	id        uint64
+	ch        chan M
+	policy    DeliveryPolicy
+	onDropped func()
+
+	statsMu    sync.Mutex
+	delivered  int64
+	dropped    int64
+	lastDropAt time.Time
+}
+
+func (s *subscriber[M]) recordDelivered() {
+	s.statsMu.Lock()
+	s.delivered++
+	s.statsMu.Unlock()
+}
+
+func (s *subscriber[M]) recordDropped() {
+	s.statsMu.Lock()
+	s.dropped++
+	s.lastDropAt = time.Now()
+	s.statsMu.Unlock()
+}
+
+func (s *subscriber[M]) stats() SubStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	return SubStats{Delivered: s.delivered, Dropped: s.dropped, LastDropAt: s.lastDropAt}
+}
+
+// retainedMsg is one entry in a topic's retention ring.
+type retainedMsg[M any] struct {
+	msg M
+	at  time.Time
 }
 
-func (t *topic) run() {
+type topic[M any] struct {
+	count     int64
+	nextSubID uint64
+	pubChan   chan M
+	subs      map[uint64]*subscriber[M]
+	chanIDs   map[<-chan M]uint64
+	retain    []retainedMsg[M]
+	retainCap int
+	retainTTL time.Duration
+	mu        sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func (t *topic[M]) run() {
 	go func() {
 		<-t.ctx.Done()
 		close(t.pubChan)
@@ -60,7 +200,7 @@ func (t *topic) run() {
 	}
 }
 
-func (t *topic) Pub(msg interface{}) error {
+func (t *topic[M]) Pub(msg M) error {
 	select {
 	case <-t.ctx.Done():
 		return ErrTopicAlreadyClosed
@@ -71,36 +211,200 @@ func (t *topic) Pub(msg interface{}) error {
 	return nil
 }
 
-func (t *topic) pub(msg interface{}) {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
+func (t *topic[M]) pub(msg M) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.retainMsg(msg)
+
+	// Two passes: first every subscriber whose policy tolerates a full
+	// buffer (DropNewest, DropOldest, Disconnect) gets its non-blocking
+	// attempt, then the full Block sends happen. Otherwise one stalled Block
+	// subscriber, reached first in map iteration order, would hold t.mu and
+	// delay delivery to topic-mates that chose a policy specifically to
+	// avoid that.
+	var toDisconnect []*subscriber[M]
+	var toBlock []*subscriber[M]
+	for _, s := range t.subs {
+		switch t.tryDeliver(s, msg) {
+		case statusDisconnect:
+			toDisconnect = append(toDisconnect, s)
+		case statusMustBlock:
+			toBlock = append(toBlock, s)
+		}
+	}
+
+	for _, s := range toBlock {
+		s.ch <- msg
+		s.recordDelivered()
+	}
+	for _, s := range toDisconnect {
+		t.unsub(s.id)
+		if s.onDropped != nil {
+			s.onDropped()
+		}
+	}
+}
+
+// retainMsg appends msg to the retention ring, evicting expired and
+// overflowing entries. Callers must hold t.mu.
+func (t *topic[M]) retainMsg(msg M) {
+	if t.retainCap <= 0 {
+		return
+	}
+
+	t.evictExpiredRetain()
+
+	t.retain = append(t.retain, retainedMsg[M]{msg: msg, at: time.Now()})
+	if over := len(t.retain) - t.retainCap; over > 0 {
+		t.retain = t.retain[over:]
+	}
+}
+
+// evictExpiredRetain drops retained messages older than retainTTL. Callers
+// must hold t.mu.
+func (t *topic[M]) evictExpiredRetain() {
+	if t.retainTTL <= 0 || len(t.retain) == 0 {
+		return
+	}
 
-	for _, subChan := range t.subChans {
-		subChan <- msg
+	cutoff := time.Now().Add(-t.retainTTL)
+	i := 0
+	for i < len(t.retain) && t.retain[i].at.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		t.retain = t.retain[i:]
 	}
 }
 
-func (t *topic) Sub() (<-chan interface{}, error) {
+// deliverStatus reports what pub must still do for a subscriber after
+// tryDeliver's non-blocking attempt.
+type deliverStatus int
+
+const (
+	// statusDone means the subscriber needs no further action this publish.
+	statusDone deliverStatus = iota
+	// statusMustBlock means the subscriber's Block policy requires a
+	// blocking send, deferred so it can't hold up other subscribers.
+	statusMustBlock
+	// statusDisconnect means the subscriber should be unsubscribed.
+	statusDisconnect
+)
+
+// tryDeliver makes a non-blocking attempt to hand msg to s, applying its
+// DeliveryPolicy immediately for every policy except Block, whose blocking
+// send pub defers until every other subscriber has had its turn.
+func (t *topic[M]) tryDeliver(s *subscriber[M], msg M) deliverStatus {
+	select {
+	case s.ch <- msg:
+		s.recordDelivered()
+		return statusDone
+	default:
+	}
+
+	switch s.policy {
+	case DropNewest:
+		s.recordDropped()
+		return statusDone
+
+	case DropOldest:
+		select {
+		case <-s.ch:
+			s.recordDropped()
+		default:
+		}
+		select {
+		case s.ch <- msg:
+			s.recordDelivered()
+		default:
+			s.recordDropped()
+		}
+		return statusDone
+
+	case Disconnect:
+		s.recordDropped()
+		return statusDisconnect
+
+	default: // Block
+		return statusMustBlock
+	}
+}
+
+func (t *topic[M]) Sub() (<-chan M, UnsubscribeFunc, error) {
+	return t.SubWithOptions(SubOptions{Policy: Block})
+}
+
+func (t *topic[M]) SubWithOptions(opts SubOptions) (<-chan M, UnsubscribeFunc, error) {
+	select {
+	case <-t.ctx.Done():
+		return nil, nil, ErrTopicAlreadyClosed
+	default:
+	}
+
+	bufSize := opts.BufferSize
+	if bufSize <= 0 {
+		bufSize = cap(t.pubChan)
+	}
+
+	id := atomic.AddUint64(&t.nextSubID, 1)
+	ch := make(chan M, bufSize)
+
+	t.mu.Lock()
+	t.subs[id] = &subscriber[M]{id: id, ch: ch, policy: opts.Policy, onDropped: opts.OnSubscriberDropped}
+	t.chanIDs[ch] = id
+	t.mu.Unlock()
+
+	return ch, func() { t.unsubByID(id) }, nil
+}
+
+func (t *topic[M]) SubWithReplay(n int) (<-chan M, UnsubscribeFunc, error) {
 	select {
 	case <-t.ctx.Done():
-		return nil, ErrTopicAlreadyClosed
+		return nil, nil, ErrTopicAlreadyClosed
 	default:
 	}
 
+	id := atomic.AddUint64(&t.nextSubID, 1)
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	ch := make(chan interface{}, cap(t.pubChan))
-	t.subChans[ch] = ch
-	return ch, nil
+	t.evictExpiredRetain()
+	backlog := t.retain
+	if n >= 0 && n < len(backlog) {
+		backlog = backlog[len(backlog)-n:]
+	}
+
+	bufSize := cap(t.pubChan)
+	if len(backlog) > bufSize {
+		bufSize = len(backlog)
+	}
+
+	ch := make(chan M, bufSize)
+	for _, m := range backlog {
+		ch <- m.msg
+	}
+
+	t.subs[id] = &subscriber[M]{id: id, ch: ch, policy: Block}
+	t.chanIDs[ch] = id
+
+	return ch, func() { t.unsubByID(id) }, nil
 }
 
-func (t *topic) unsub(ch <-chan interface{}) {
-	close(t.subChans[ch])
-	delete(t.subChans, ch)
+// unsub closes and removes the subscriber for id. Callers must hold t.mu.
+func (t *topic[M]) unsub(id uint64) {
+	s, ok := t.subs[id]
+	if !ok {
+		return
+	}
+
+	close(s.ch)
+	delete(t.subs, id)
+	delete(t.chanIDs, s.ch)
 }
 
-func (t *topic) Unsub(ch <-chan interface{}) error {
+func (t *topic[M]) unsubByID(id uint64) error {
 	select {
 	case <-t.ctx.Done():
 		return ErrTopicAlreadyClosed
@@ -110,31 +414,60 @@ func (t *topic) Unsub(ch <-chan interface{}) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.unsub(ch)
+	t.unsub(id)
 	return nil
 }
 
-func (t *topic) unsubAll() {
+// Unsub is a back-compat shim for callers that still track the channel
+// rather than the UnsubscribeFunc returned from Sub/SubWithOptions.
+func (t *topic[M]) Unsub(ch <-chan M) error {
+	t.mu.RLock()
+	id, ok := t.chanIDs[ch]
+	t.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return t.unsubByID(id)
+}
+
+func (t *topic[M]) unsubAll() {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	for ch := range t.subChans {
-		t.unsub(ch)
+	for id := range t.subs {
+		t.unsub(id)
 	}
-	t.subChans = nil
+	t.subs = nil
+	t.chanIDs = nil
+}
+
+func (t *topic[M]) SubLen() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.subs)
 }
 
-func (t *topic) SubLen() int {
+func (t *topic[M]) Retains() bool {
+	return t.retainCap > 0
+}
+
+func (t *topic[M]) Stats() map[<-chan M]SubStats {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	return len(t.subChans)
+	stats := make(map[<-chan M]SubStats, len(t.subs))
+	for _, s := range t.subs {
+		stats[s.ch] = s.stats()
+	}
+	return stats
 }
 
-func (t *topic) Close() {
+func (t *topic[M]) Close() {
 	t.cancel()
 }
 
-func (t *topic) Context() context.Context {
+func (t *topic[M]) Context() context.Context {
 	return t.ctx
 }
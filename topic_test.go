@@ -0,0 +1,254 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestDeliveryIsolatesBlockSubscriber guards against a stalled Block
+// subscriber holding up delivery to topic-mates that chose a policy
+// specifically to avoid head-of-line blocking.
+func TestDeliveryIsolatesBlockSubscriber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopicWithOptions[int](ctx, TopicOptions{QueueSize: 1}).(*topic[int])
+
+	blockCh, _, err := top.SubWithOptions(SubOptions{Policy: Block, BufferSize: 1})
+	if err != nil {
+		t.Fatalf("SubWithOptions: %v", err)
+	}
+	dropCh, _, err := top.SubWithOptions(SubOptions{Policy: DropNewest, BufferSize: 1})
+	if err != nil {
+		t.Fatalf("SubWithOptions: %v", err)
+	}
+
+	// Call pub directly (bypassing the topic's own run goroutine, which is
+	// otherwise idle here) so the test controls exactly when each publish
+	// happens. Both buffers are empty, so this delivers to both instantly.
+	top.pub(1)
+
+	top.mu.RLock()
+	dropSub := top.subs[top.chanIDs[dropCh]]
+	top.mu.RUnlock()
+
+	if s := dropSub.stats(); s.Delivered != 1 {
+		t.Fatalf("dropCh Delivered = %d, want 1", s.Delivered)
+	}
+	_ = blockCh
+
+	// blockCh's buffer is now full and nothing ever drains it, so this call
+	// blocks forever inside its deferred Block send; run it on its own
+	// goroutine. dropCh chose DropNewest specifically to avoid being held up
+	// by that, so it must still see (and drop) this message promptly.
+	go top.pub(2)
+
+	waitForSubStats(t, dropSub, func(s SubStats) bool { return s.Dropped == 1 })
+}
+
+// TestTopicGenericRoundTrip checks that a typed Topic hands a subscriber back
+// exactly the value published, with no boxing/unboxing or type assertion
+// required on either side.
+func TestTopicGenericRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopic[string](ctx, 1)
+	ch, unsub, err := top.Sub()
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	defer unsub()
+
+	if err := top.Pub("hello"); err != nil {
+		t.Fatalf("Pub: %v", err)
+	}
+
+	select {
+	case msg := <-ch:
+		if msg != "hello" {
+			t.Fatalf("got %q, want %q", msg, "hello")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
+
+// TestDeliveryPolicyDropNewest checks that a full DropNewest subscriber keeps
+// its buffered message and discards the one being published.
+func TestDeliveryPolicyDropNewest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopic[int](ctx, 1)
+	ch, unsub, err := top.SubWithOptions(SubOptions{Policy: DropNewest, BufferSize: 1})
+	if err != nil {
+		t.Fatalf("SubWithOptions: %v", err)
+	}
+	defer unsub()
+
+	_ = top.Pub(1)
+	waitForStats(t, top, ch, func(s SubStats) bool { return s.Delivered == 1 })
+
+	_ = top.Pub(2)
+	waitForStats(t, top, ch, func(s SubStats) bool { return s.Dropped == 1 })
+
+	if got := <-ch; got != 1 {
+		t.Fatalf("buffered message = %d, want 1 (DropNewest must not touch the existing buffer)", got)
+	}
+}
+
+// TestDeliveryPolicyDropOldest checks that a full DropOldest subscriber
+// evicts its stale buffered message to make room for the new one.
+func TestDeliveryPolicyDropOldest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopic[int](ctx, 1)
+	ch, unsub, err := top.SubWithOptions(SubOptions{Policy: DropOldest, BufferSize: 1})
+	if err != nil {
+		t.Fatalf("SubWithOptions: %v", err)
+	}
+	defer unsub()
+
+	_ = top.Pub(1)
+	waitForStats(t, top, ch, func(s SubStats) bool { return s.Delivered == 1 })
+
+	_ = top.Pub(2)
+	waitForStats(t, top, ch, func(s SubStats) bool { return s.Dropped == 1 && s.Delivered == 2 })
+
+	if got := <-ch; got != 2 {
+		t.Fatalf("buffered message = %d, want 2 (DropOldest should replace the stale value)", got)
+	}
+}
+
+// TestDeliveryPolicyDisconnect checks that a full Disconnect subscriber is
+// unsubscribed, its channel closed (after yielding whatever was already
+// buffered), and its OnSubscriberDropped callback invoked.
+func TestDeliveryPolicyDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopic[int](ctx, 1)
+
+	dropped := make(chan struct{}, 1)
+	ch, unsub, err := top.SubWithOptions(SubOptions{
+		Policy:              Disconnect,
+		BufferSize:          1,
+		OnSubscriberDropped: func() { dropped <- struct{}{} },
+	})
+	if err != nil {
+		t.Fatalf("SubWithOptions: %v", err)
+	}
+	defer unsub()
+
+	_ = top.Pub(1)
+	waitForStats(t, top, ch, func(s SubStats) bool { return s.Delivered == 1 })
+
+	_ = top.Pub(2) // buffer (size 1) is already full of message 1: triggers Disconnect
+
+	select {
+	case <-dropped:
+	case <-time.After(time.Second):
+		t.Fatal("OnSubscriberDropped was not called")
+	}
+
+	if got, ok := <-ch; !ok || got != 1 {
+		t.Fatalf("expected buffered message 1, got %d ok=%v", got, ok)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed after Disconnect")
+	}
+}
+
+// TestSubWithReplayOrdering checks that a late subscriber receives the
+// topic's retained backlog, oldest first, before any live traffic.
+func TestSubWithReplayOrdering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	top := NewTopicWithOptions[int](ctx, TopicOptions{QueueSize: 1, Retain: 2}).(*topic[int])
+
+	_ = top.Pub(1)
+	_ = top.Pub(2)
+	_ = top.Pub(3) // retain cap is 2, so the ring should settle on [2, 3]
+
+	waitFor(t, time.Second, func() bool {
+		top.mu.RLock()
+		defer top.mu.RUnlock()
+		return len(top.retain) == 2 && top.retain[1].msg == 3
+	})
+
+	ch, unsub, err := top.SubWithReplay(-1)
+	if err != nil {
+		t.Fatalf("SubWithReplay: %v", err)
+	}
+	defer unsub()
+
+	for _, want := range []int{2, 3} {
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Fatalf("replay got %d, want %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for replay message")
+		}
+	}
+}
+
+// waitFor polls cond until it reports true, failing the test if it doesn't
+// within timeout.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not satisfied within %s", timeout)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitForStats polls top.Stats() until pred is satisfied for ch's entry,
+// failing the test if that doesn't happen within a second. Delivery to a
+// topic's subscribers happens asynchronously on the topic's own goroutine,
+// so tests observe it by polling rather than assuming Pub has finished by
+// the time it returns.
+func waitForStats[M any](t *testing.T, top Topic[M], ch <-chan M, pred func(SubStats) bool) SubStats {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if s, ok := top.Stats()[ch]; ok && pred(s) {
+			return s
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stats condition not satisfied within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitForSubStats polls a subscriber's own stats directly, via its
+// independent statsMu, so it stays usable even once the topic's t.mu is
+// permanently held (e.g. by another subscriber's stalled Block send).
+func waitForSubStats[M any](t *testing.T, s *subscriber[M], pred func(SubStats) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if pred(s.stats()) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("subscriber stats condition not satisfied within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}